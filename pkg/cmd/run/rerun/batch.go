@@ -0,0 +1,259 @@
+package rerun
+
+import (
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"github.com/cli/cli/v2/api"
+	"github.com/cli/cli/v2/internal/ghrepo"
+	"github.com/cli/cli/v2/pkg/cmd/run/shared"
+	"github.com/cli/cli/v2/pkg/iostreams"
+)
+
+// runFilterKeys are the only keys parseRunFilterQuery accepts. Anything
+// else is rejected outright rather than silently ignored, since an
+// over-matching filter on this bulk, destructive command reruns more than
+// the caller intended.
+var runFilterKeys = map[string]bool{
+	"status":     true,
+	"conclusion": true,
+	"branch":     true,
+	"workflow":   true,
+	"event":      true,
+	"older-than": true,
+}
+
+// batchWorkers bounds how many rerun requests are in flight at once so a
+// large `--query` result doesn't hammer the API all at once.
+const batchWorkers = 5
+
+// maxQueryRuns bounds how many runs a --query filter can pull back. There is
+// no "unlimited" sentinel for shared.GetRunsWithFilter's limit, so pick an
+// explicit, generous bound instead of relying on an undocumented 0.
+const maxQueryRuns = 500
+
+type batchResult struct {
+	RunID  int64
+	Verb   string
+	Result string
+	Err    error
+}
+
+// batchRerun reruns every run in opts.RunIDs, or every run matching
+// opts.Query if no explicit IDs were given, concurrently and reports a
+// summary table. It returns an error if any single rerun request failed.
+func batchRerun(opts *RerunOptions) error {
+	c, err := opts.HttpClient()
+	if err != nil {
+		return fmt.Errorf("failed to create http client: %w", err)
+	}
+	client := api.NewClientFromHTTP(c)
+
+	repo, err := opts.BaseRepo()
+	if err != nil {
+		return fmt.Errorf("failed to determine base repo: %w", err)
+	}
+
+	if err := checkRerunAuthorized(client, repo); err != nil {
+		return err
+	}
+
+	runIDs := opts.RunIDs
+	if opts.Query != "" && len(runIDs) == 0 {
+		filter, err := parseRunFilterQuery(client, repo, opts.Query)
+		if err != nil {
+			return err
+		}
+
+		opts.IO.StartProgressIndicator()
+		runs, err := shared.GetRunsWithFilter(client, repo, nil, maxQueryRuns, filter)
+		opts.IO.StopProgressIndicator()
+		if err != nil {
+			return fmt.Errorf("failed to get runs: %w", err)
+		}
+		if len(runs) == maxQueryRuns {
+			fmt.Fprintf(opts.IO.ErrOut, "warning: --query matched at least %d runs; only the first %d were considered\n", maxQueryRuns, maxQueryRuns)
+		}
+
+		runIDs = make([]string, len(runs))
+		for i, run := range runs {
+			runIDs[i] = fmt.Sprintf("%d", run.ID)
+		}
+	}
+
+	if len(runIDs) == 0 {
+		return fmt.Errorf("no runs to rerun")
+	}
+
+	results := rerunBatch(opts, client, repo, runIDs)
+
+	printBatchResults(opts.IO, results)
+
+	for _, r := range results {
+		if r.Err != nil {
+			return fmt.Errorf("%d rerun request(s) failed", countFailed(results))
+		}
+	}
+	return nil
+}
+
+func rerunBatch(opts *RerunOptions, client *api.Client, repo ghrepo.Interface, runIDs []string) []batchResult {
+	results := make([]batchResult, len(runIDs))
+	sem := make(chan struct{}, batchWorkers)
+	var wg sync.WaitGroup
+
+	for i, runID := range runIDs {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, runID string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			results[i] = rerunOneForBatch(opts, client, repo, runID)
+		}(i, runID)
+	}
+
+	wg.Wait()
+	return results
+}
+
+func rerunOneForBatch(opts *RerunOptions, client *api.Client, repo ghrepo.Interface, runID string) batchResult {
+	verb := "rerun"
+	if opts.OnlyFailed {
+		verb = "rerun-failed-jobs"
+	}
+
+	id, _ := strconv.ParseInt(runID, 10, 64)
+	res := batchResult{RunID: id, Verb: verb}
+
+	run, err := shared.GetRun(client, repo, runID)
+	if err != nil {
+		res.Err = fmt.Errorf("failed to get run: %w", err)
+		res.Result = "failed"
+		return res
+	}
+
+	if err := rerunRun(opts.IO, client, repo, run, opts.OnlyFailed, opts.Debug, opts.Attempts); err != nil {
+		res.Err = err
+		res.Result = "failed"
+		return res
+	}
+
+	res.Result = "ok"
+	return res
+}
+
+func countFailed(results []batchResult) int {
+	n := 0
+	for _, r := range results {
+		if r.Err != nil {
+			n++
+		}
+	}
+	return n
+}
+
+func printBatchResults(ios *iostreams.IOStreams, results []batchResult) {
+	w := tabwriter.NewWriter(ios.Out, 0, 2, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tVERB\tRESULT\tERROR")
+	for _, r := range results {
+		errMsg := ""
+		if r.Err != nil {
+			errMsg = r.Err.Error()
+		}
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\n", r.RunID, r.Verb, r.Result, errMsg)
+	}
+	w.Flush()
+}
+
+// parseRunFilterQuery turns a simple "key=value key=value" filter
+// expression into a shared.GetRunsWithFilter predicate. Supported keys:
+// status (queued/in_progress/completed), conclusion (success/failure/...),
+// branch, workflow (file name or display name), event, and older-than (a Go
+// duration, e.g. "24h"). Unknown keys are rejected rather than ignored, so a
+// typo can't silently widen a bulk rerun to every run in the repo.
+func parseRunFilterQuery(client *api.Client, repo ghrepo.Interface, query string) (func(shared.Run) bool, error) {
+	filters := map[string]string{}
+	for _, field := range strings.Fields(query) {
+		parts := strings.SplitN(field, "=", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("invalid filter %q; expected key=value", field)
+		}
+		if !runFilterKeys[parts[0]] {
+			return nil, fmt.Errorf("unknown filter key %q", parts[0])
+		}
+		filters[parts[0]] = parts[1]
+	}
+
+	var olderThan time.Duration
+	if v, ok := filters["older-than"]; ok {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid older-than duration %q: %w", v, err)
+		}
+		olderThan = d
+	}
+
+	workflows := &workflowLookupCache{client: client, repo: repo, byID: map[int64][2]string{}}
+	workflowMatch, hasWorkflowFilter := filters["workflow"]
+
+	return func(run shared.Run) bool {
+		if v, ok := filters["status"]; ok && string(run.Status) != v {
+			return false
+		}
+		if v, ok := filters["conclusion"]; ok && string(run.Conclusion) != v {
+			return false
+		}
+		if v, ok := filters["branch"]; ok && run.HeadBranch != v {
+			return false
+		}
+		if v, ok := filters["event"]; ok && run.Event != v {
+			return false
+		}
+		if olderThan > 0 && time.Since(run.CreatedAt) < olderThan {
+			return false
+		}
+		if hasWorkflowFilter {
+			fileName, displayName, ok := workflows.lookup(run.WorkflowID)
+			if !ok || (fileName != workflowMatch && displayName != workflowMatch) {
+				return false
+			}
+		}
+		return true
+	}, nil
+}
+
+// workflowLookupCache resolves a run's workflow file name and display name,
+// caching results by workflow ID. It's safe for concurrent use since
+// GetRunsWithFilter's predicate may be evaluated from more than one
+// goroutine.
+type workflowLookupCache struct {
+	client *api.Client
+	repo   ghrepo.Interface
+
+	mu   sync.Mutex
+	byID map[int64][2]string // [fileName, displayName]
+}
+
+func (c *workflowLookupCache) lookup(workflowID int64) (fileName, displayName string, ok bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if names, cached := c.byID[workflowID]; cached {
+		return names[0], names[1], true
+	}
+
+	workflow, err := shared.GetWorkflow(c.client, c.repo, workflowID)
+	if err != nil {
+		return "", "", false
+	}
+
+	fileName = path.Base(workflow.Path)
+	displayName = workflow.Name
+	c.byID[workflowID] = [2]string{fileName, displayName}
+	return fileName, displayName, true
+}
@@ -0,0 +1,107 @@
+package rerun
+
+import (
+	"testing"
+	"time"
+
+	"github.com/cli/cli/v2/pkg/cmd/run/shared"
+)
+
+func TestParseRunFilterQuery(t *testing.T) {
+	tests := []struct {
+		name    string
+		query   string
+		run     shared.Run
+		want    bool
+		wantErr bool
+	}{
+		{
+			name:  "status match",
+			query: "status=completed",
+			run:   shared.Run{Status: shared.Completed},
+			want:  true,
+		},
+		{
+			name:  "status mismatch",
+			query: "status=completed",
+			run:   shared.Run{Status: shared.InProgress},
+			want:  false,
+		},
+		{
+			name:  "conclusion match",
+			query: "conclusion=failure",
+			run:   shared.Run{Conclusion: shared.Failure},
+			want:  true,
+		},
+		{
+			name:  "conclusion mismatch",
+			query: "conclusion=failure",
+			run:   shared.Run{Conclusion: shared.Success},
+			want:  false,
+		},
+		{
+			name:  "combined filters all match",
+			query: "status=completed conclusion=failure branch=main",
+			run:   shared.Run{Status: shared.Completed, Conclusion: shared.Failure, HeadBranch: "main"},
+			want:  true,
+		},
+		{
+			name:  "combined filters one mismatches",
+			query: "status=completed conclusion=failure branch=main",
+			run:   shared.Run{Status: shared.Completed, Conclusion: shared.Failure, HeadBranch: "develop"},
+			want:  false,
+		},
+		{
+			name:  "event match",
+			query: "event=push",
+			run:   shared.Run{Event: "push"},
+			want:  true,
+		},
+		{
+			name:  "older-than excludes recent run",
+			query: "older-than=24h",
+			run:   shared.Run{CreatedAt: time.Now().Add(-time.Hour)},
+			want:  false,
+		},
+		{
+			name:  "older-than includes old run",
+			query: "older-than=24h",
+			run:   shared.Run{CreatedAt: time.Now().Add(-48 * time.Hour)},
+			want:  true,
+		},
+		{
+			name:    "invalid older-than duration",
+			query:   "older-than=soon",
+			wantErr: true,
+		},
+		{
+			name:    "unknown key is rejected",
+			query:   "statuss=failure",
+			wantErr: true,
+		},
+		{
+			name:    "malformed field is rejected",
+			query:   "status",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter, err := parseRunFilterQuery(nil, nil, tt.query)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected an error, got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if got := filter(tt.run); got != tt.want {
+				t.Errorf("filter(%+v) = %v, want %v", tt.run, got, tt.want)
+			}
+		})
+	}
+}
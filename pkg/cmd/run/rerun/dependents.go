@@ -0,0 +1,231 @@
+package rerun
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	"github.com/cli/cli/v2/api"
+	"github.com/cli/cli/v2/internal/ghrepo"
+	"github.com/cli/cli/v2/pkg/cmd/run/shared"
+	"github.com/cli/cli/v2/pkg/iostreams"
+	"gopkg.in/yaml.v3"
+)
+
+// workflowJob is one job as declared in the workflow YAML: its "needs:" list
+// (by YAML key) and its own "name:" override, if any.
+type workflowJob struct {
+	Needs []string
+	Name  string
+}
+
+// workflowJobGraph maps YAML job keys to their declarations, and resolves
+// the API's display name for a job back to the key it came from.
+type workflowJobGraph map[string]workflowJob
+
+// resolveKey maps a shared.Job's display name back to its YAML job key.
+// The Jobs API only returns the rendered name, which is the key verbatim
+// unless the job sets "name:" and/or is expanded from a matrix (rendered as
+// "<name> (<matrix values>)"), so this tries, in order: an exact key match,
+// an exact "name:" match, and then both of those again with any trailing
+// " (...)" matrix suffix stripped. If nothing matches, the display name is
+// returned as-is so callers degrade to the old (pre-fix) behavior rather
+// than silently dropping the job.
+func (g workflowJobGraph) resolveKey(displayName string) string {
+	if _, ok := g[displayName]; ok {
+		return displayName
+	}
+	for key, job := range g {
+		if job.Name != "" && job.Name == displayName {
+			return key
+		}
+	}
+
+	base := displayName
+	if i := strings.LastIndex(base, " ("); i >= 0 && strings.HasSuffix(base, ")") {
+		base = base[:i]
+	}
+	if base != displayName {
+		if _, ok := g[base]; ok {
+			return base
+		}
+		for key, job := range g {
+			if job.Name != "" && job.Name == base {
+				return key
+			}
+		}
+	}
+
+	return displayName
+}
+
+// dependentsOf returns the jobs (by YAML key) that transitively depend on
+// target, directly or through another dependent.
+func (g workflowJobGraph) dependentsOf(target string) []string {
+	direct := map[string]bool{}
+	for key, job := range g {
+		for _, n := range job.Needs {
+			if n == target {
+				direct[key] = true
+				break
+			}
+		}
+	}
+
+	all := map[string]bool{}
+	var collect func(string)
+	collect = func(key string) {
+		if all[key] {
+			return
+		}
+		all[key] = true
+		for _, dependent := range g.dependentsOf(key) {
+			collect(dependent)
+		}
+	}
+	for key := range direct {
+		collect(key)
+	}
+
+	result := make([]string, 0, len(all))
+	for key := range all {
+		result = append(result, key)
+	}
+	return result
+}
+
+// fetchWorkflowJobGraph downloads and parses the workflow file backing run,
+// returning each job's "needs:" dependencies and name override keyed by its
+// YAML job key.
+func fetchWorkflowJobGraph(client *api.Client, repo ghrepo.Interface, run *shared.Run) (workflowJobGraph, error) {
+	workflow, err := shared.GetWorkflow(client, repo, run.WorkflowID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get workflow: %w", err)
+	}
+
+	path := fmt.Sprintf("repos/%s/contents/%s?ref=%s", ghrepo.FullName(repo), workflow.Path, run.HeadSha)
+	var result struct {
+		Content string `json:"content"`
+	}
+	if err := client.REST(repo.RepoHost(), "GET", path, nil, &result); err != nil {
+		return nil, fmt.Errorf("failed to fetch workflow file: %w", err)
+	}
+
+	raw, err := base64.StdEncoding.DecodeString(result.Content)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode workflow file: %w", err)
+	}
+
+	var doc struct {
+		Jobs map[string]struct {
+			Name  string    `yaml:"name"`
+			Needs yaml.Node `yaml:"needs"`
+		} `yaml:"jobs"`
+	}
+	if err := yaml.Unmarshal(raw, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse workflow file: %w", err)
+	}
+
+	graph := workflowJobGraph{}
+	for jobKey, job := range doc.Jobs {
+		wj := workflowJob{Name: job.Name}
+		switch job.Needs.Kind {
+		case yaml.ScalarNode:
+			wj.Needs = []string{job.Needs.Value}
+		case yaml.SequenceNode:
+			var list []string
+			_ = job.Needs.Decode(&list)
+			wj.Needs = list
+		}
+		graph[jobKey] = wj
+	}
+
+	return graph, nil
+}
+
+// rerunWithDependents reruns every job in the run that transitively needs
+// selectedJob, so downstream work is retriggered too. selectedJob itself is
+// not rerun here.
+func rerunWithDependents(ios *iostreams.IOStreams, client *api.Client, repo ghrepo.Interface, run *shared.Run, selectedJob *shared.Job, debug bool, attempts int) error {
+	graph, err := fetchWorkflowJobGraph(client, repo, run)
+	if err != nil {
+		return err
+	}
+
+	selectedKey := graph.resolveKey(selectedJob.Name)
+	dependentKeys := graph.dependentsOf(selectedKey)
+	if len(dependentKeys) == 0 {
+		return nil
+	}
+
+	jobs, err := shared.GetJobs(client, repo, run)
+	if err != nil {
+		return fmt.Errorf("failed to get jobs: %w", err)
+	}
+
+	dependentKeySet := map[string]bool{}
+	for _, key := range dependentKeys {
+		dependentKeySet[key] = true
+	}
+
+	for _, job := range jobs {
+		if job.ID == selectedJob.ID || !dependentKeySet[graph.resolveKey(job.Name)] {
+			continue
+		}
+		if err := rerunJob(ios, client, repo, &job, debug, attempts); err != nil {
+			return fmt.Errorf("failed to rerun dependent job %s: %w", job.Name, err)
+		}
+		if ios.IsStdoutTTY() {
+			cs := ios.ColorScheme()
+			fmt.Fprintf(ios.Out, "%s Requested rerun of dependent job %s\n", cs.SuccessIcon(), cs.Cyanf("%d", job.ID))
+		}
+	}
+
+	return nil
+}
+
+// warnIfRerunFailedWillBlock checks whether every job left to make progress
+// on run (i.e. every job that isn't itself failed) needs a failed job,
+// meaning `--failed` will requeue work that can't actually start until those
+// failed jobs finish, and prints a warning to ErrOut if so.
+func warnIfRerunFailedWillBlock(ios *iostreams.IOStreams, client *api.Client, repo ghrepo.Interface, run *shared.Run) {
+	jobs, err := shared.GetJobs(client, repo, run)
+	if err != nil {
+		return
+	}
+
+	graph, err := fetchWorkflowJobGraph(client, repo, run)
+	if err != nil {
+		return
+	}
+
+	failedKeys := map[string]bool{}
+	var remaining []shared.Job
+	for _, job := range jobs {
+		if job.Conclusion == shared.Failure {
+			failedKeys[graph.resolveKey(job.Name)] = true
+		} else {
+			remaining = append(remaining, job)
+		}
+	}
+	if len(remaining) == 0 || len(failedKeys) == 0 {
+		return
+	}
+
+	for _, job := range remaining {
+		jobNeeds := graph[graph.resolveKey(job.Name)].Needs
+		blocked := false
+		for _, n := range jobNeeds {
+			if failedKeys[n] {
+				blocked = true
+				break
+			}
+		}
+		if !blocked {
+			return
+		}
+	}
+
+	cs := ios.ColorScheme()
+	fmt.Fprintf(ios.ErrOut, "%s rerunning failed jobs will not make progress: every remaining job needs a failed job and will stay queued until it completes\n", cs.WarningIcon())
+}
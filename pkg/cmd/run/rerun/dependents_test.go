@@ -0,0 +1,56 @@
+package rerun
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestWorkflowJobGraph_resolveKey(t *testing.T) {
+	graph := workflowJobGraph{
+		"build": workflowJob{},
+		"test":  workflowJob{Name: "Run the test suite"},
+	}
+
+	tests := []struct {
+		name        string
+		displayName string
+		want        string
+	}{
+		{name: "exact key match", displayName: "build", want: "build"},
+		{name: "name override match", displayName: "Run the test suite", want: "test"},
+		{name: "matrix suffix on key", displayName: "build (ubuntu, 1.20)", want: "build"},
+		{name: "matrix suffix on name override", displayName: "Run the test suite (ubuntu, 1.20)", want: "test"},
+		{name: "no match falls back to display name", displayName: "deploy", want: "deploy"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := graph.resolveKey(tt.displayName); got != tt.want {
+				t.Errorf("resolveKey(%q) = %q, want %q", tt.displayName, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWorkflowJobGraph_dependentsOf(t *testing.T) {
+	graph := workflowJobGraph{
+		"build":  workflowJob{},
+		"test":   workflowJob{Needs: []string{"build"}},
+		"lint":   workflowJob{Needs: []string{"build"}},
+		"deploy": workflowJob{Needs: []string{"test", "lint"}},
+		"notify": workflowJob{Needs: []string{"deploy"}},
+	}
+
+	got := graph.dependentsOf("build")
+	sort.Strings(got)
+	want := []string{"deploy", "lint", "notify", "test"}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("dependentsOf(\"build\") = %v, want %v", got, want)
+	}
+
+	if got := graph.dependentsOf("notify"); len(got) != 0 {
+		t.Errorf("dependentsOf(\"notify\") = %v, want empty", got)
+	}
+}
@@ -1,9 +1,12 @@
 package rerun
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"net/http"
+	"time"
 
 	"github.com/cli/cli/v2/api"
 	"github.com/cli/cli/v2/internal/ghrepo"
@@ -13,15 +16,25 @@ import (
 	"github.com/spf13/cobra"
 )
 
+const defaultWatchInterval = 3 * time.Second
+
 type RerunOptions struct {
 	HttpClient func() (*http.Client, error)
 	IO         *iostreams.IOStreams
 	BaseRepo   func() (ghrepo.Interface, error)
 
 	RunID      string
+	RunIDs     []string
+	Query      string
 	OnlyFailed bool
 	JobID      string
 
+	Watch          bool
+	Interval       time.Duration
+	Debug          bool
+	Attempts       int
+	WithDependents bool
+
 	Prompt bool
 }
 
@@ -32,13 +45,25 @@ func NewCmdRerun(f *cmdutil.Factory, runF func(*RerunOptions) error) *cobra.Comm
 	}
 
 	cmd := &cobra.Command{
-		Use:   "rerun [<run-id>]",
+		Use:   "rerun [<run-id>...]",
 		Short: "Rerun a failed run",
-		Args:  cobra.MaximumNArgs(1),
+		Args:  cobra.ArbitraryArgs,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			// support `-R, --repo` override
 			opts.BaseRepo = f.BaseRepo
 
+			if opts.Attempts < 1 {
+				return cmdutil.FlagErrorf("--attempts must be at least 1")
+			}
+
+			if opts.Query != "" || len(args) > 1 {
+				opts.RunIDs = args
+				if runF != nil {
+					return runF(opts)
+				}
+				return batchRerun(opts)
+			}
+
 			if len(args) == 0 && opts.JobID == "" {
 				if !opts.IO.CanPrompt() {
 					return cmdutil.FlagErrorf("run or job ID required when not running interactively")
@@ -66,6 +91,12 @@ func NewCmdRerun(f *cmdutil.Factory, runF func(*RerunOptions) error) *cobra.Comm
 
 	cmd.Flags().BoolVar(&opts.OnlyFailed, "failed", false, "Rerun only failed jobs")
 	cmd.Flags().StringVarP(&opts.JobID, "job", "j", "", "Rerun a specific job from a run, including dependencies")
+	cmd.Flags().BoolVarP(&opts.Watch, "watch", "w", false, "Watch the rerun until it completes, printing its progress")
+	cmd.Flags().DurationVar(&opts.Interval, "interval", defaultWatchInterval, "Refresh interval when watching")
+	cmd.Flags().BoolVar(&opts.Debug, "debug", false, "Rerun with debug logging enabled")
+	cmd.Flags().IntVar(&opts.Attempts, "attempts", 1, "Number of times to attempt the rerun request before giving up")
+	cmd.Flags().BoolVar(&opts.WithDependents, "with-dependents", false, "Also rerun jobs that depend on the selected job via `needs`")
+	cmd.Flags().StringVar(&opts.Query, "query", "", "Rerun every run matching a filter, e.g. `conclusion=failure branch=main`")
 
 	return cmd
 }
@@ -84,6 +115,10 @@ func runRerun(opts *RerunOptions) error {
 
 	cs := opts.IO.ColorScheme()
 
+	if err := checkRerunAuthorized(client, repo); err != nil {
+		return err
+	}
+
 	runID := opts.RunID
 	jobID := opts.JobID
 	var selectedJob *shared.Job
@@ -120,7 +155,7 @@ func runRerun(opts *RerunOptions) error {
 	}
 
 	if opts.JobID != "" {
-		err = rerunJob(client, repo, selectedJob)
+		err = rerunJob(opts.IO, client, repo, selectedJob, opts.Debug, opts.Attempts)
 		if err != nil {
 			return err
 		}
@@ -130,6 +165,18 @@ func runRerun(opts *RerunOptions) error {
 				cs.Cyanf("%d", selectedJob.ID),
 				cs.Cyanf("%d", selectedJob.RunID))
 		}
+		if opts.WithDependents {
+			run, err := shared.GetRun(client, repo, runID)
+			if err != nil {
+				return fmt.Errorf("failed to get run: %w", err)
+			}
+			if err := rerunWithDependents(opts.IO, client, repo, run, selectedJob, opts.Debug, opts.Attempts); err != nil {
+				return err
+			}
+		}
+		if opts.Watch {
+			return watchRun(opts, client, repo, runID)
+		}
 	} else {
 		opts.IO.StartProgressIndicator()
 		run, err := shared.GetRun(client, repo, runID)
@@ -138,7 +185,11 @@ func runRerun(opts *RerunOptions) error {
 			return fmt.Errorf("failed to get run: %w", err)
 		}
 
-		err = rerunRun(client, repo, run, opts.OnlyFailed)
+		if opts.OnlyFailed {
+			warnIfRerunFailedWillBlock(opts.IO, client, repo, run)
+		}
+
+		err = rerunRun(opts.IO, client, repo, run, opts.OnlyFailed, opts.Debug, opts.Attempts)
 		if err != nil {
 			return err
 		}
@@ -152,12 +203,98 @@ func runRerun(opts *RerunOptions) error {
 				onlyFailedMsg,
 				cs.Cyanf("%d", run.ID))
 		}
+		if opts.Watch {
+			return watchRun(opts, client, repo, runID)
+		}
+	}
+
+	return nil
+}
+
+// watchRun polls the given run until it reaches a terminal state, printing a
+// live status view of its jobs, and returns an error if the run did not
+// conclude successfully.
+func watchRun(opts *RerunOptions, client *api.Client, repo ghrepo.Interface, runID string) error {
+	cs := opts.IO.ColorScheme()
+	interval := opts.Interval
+	if interval <= 0 {
+		interval = defaultWatchInterval
+	}
+
+	isTTY := opts.IO.IsStdoutTTY()
+	linesPrinted := 0
+
+	// Right after the rerun POST, GetRun can still return the previous
+	// attempt's Completed status for a beat before the new attempt re-enters
+	// queued. Don't treat Completed as terminal until we've actually seen
+	// the run leave that state at least once, or we'd report success
+	// without ever observing the rerun.
+	seenNotCompleted := false
+
+	var run *shared.Run
+	for {
+		opts.IO.StartProgressIndicator()
+		var err error
+		run, err = shared.GetRun(client, repo, runID)
+		if err == nil {
+			var jobs []shared.Job
+			jobs, err = shared.GetJobs(client, repo, run)
+			opts.IO.StopProgressIndicator()
+			if err != nil {
+				return fmt.Errorf("failed to get jobs: %w", err)
+			}
+
+			if isTTY {
+				if linesPrinted > 0 {
+					fmt.Fprintf(opts.IO.Out, "\x1b[%dA\x1b[J", linesPrinted)
+				}
+				linesPrinted = printWatchStatus(opts.IO.Out, cs, interval, jobs)
+			}
+		} else {
+			opts.IO.StopProgressIndicator()
+			return fmt.Errorf("failed to get run: %w", err)
+		}
+
+		if run.Status != shared.Completed {
+			seenNotCompleted = true
+		} else if seenNotCompleted {
+			break
+		}
+
+		time.Sleep(interval)
+	}
+
+	if opts.IO.IsStdoutTTY() {
+		fmt.Fprintf(opts.IO.Out, "\n%s Run %s completed with conclusion: %s\n",
+			cs.SuccessIcon(),
+			cs.Cyanf("%d", run.ID),
+			run.Conclusion)
+	}
+
+	if run.Conclusion != shared.Success {
+		return fmt.Errorf("run %d did not complete successfully: %s", run.ID, run.Conclusion)
 	}
 
 	return nil
 }
 
-func rerunRun(client *api.Client, repo ghrepo.Interface, run *shared.Run, onlyFailed bool) error {
+// printWatchStatus renders one tick of the watch view: a refresh header
+// followed by each job's current conclusion (or its in-progress status if it
+// hasn't concluded yet). It returns the number of lines written so the
+// caller can erase them before the next tick.
+func printWatchStatus(w io.Writer, cs *iostreams.ColorScheme, interval time.Duration, jobs []shared.Job) int {
+	fmt.Fprintf(w, "Refreshing run status every %s\n", interval)
+	for _, job := range jobs {
+		status := string(job.Status)
+		if job.Status == shared.Completed {
+			status = string(job.Conclusion)
+		}
+		fmt.Fprintf(w, "%s %s\n", cs.Gray(status), job.Name)
+	}
+	return len(jobs) + 1
+}
+
+func rerunRun(ios *iostreams.IOStreams, client *api.Client, repo ghrepo.Interface, run *shared.Run, onlyFailed, debug bool, attempts int) error {
 	runVerb := "rerun"
 	if onlyFailed {
 		runVerb = "rerun-failed-jobs"
@@ -165,7 +302,12 @@ func rerunRun(client *api.Client, repo ghrepo.Interface, run *shared.Run, onlyFa
 
 	path := fmt.Sprintf("repos/%s/actions/runs/%d/%s", ghrepo.FullName(repo), run.ID, runVerb)
 
-	err := client.REST(repo.RepoHost(), "POST", path, nil, nil)
+	body, err := rerunRequestBody(debug)
+	if err != nil {
+		return err
+	}
+
+	err = restWithRetry(ios, client, repo.RepoHost(), path, body, attempts)
 	if err != nil {
 		var httpError api.HTTPError
 		if errors.As(err, &httpError) && httpError.StatusCode == 403 {
@@ -176,10 +318,15 @@ func rerunRun(client *api.Client, repo ghrepo.Interface, run *shared.Run, onlyFa
 	return nil
 }
 
-func rerunJob(client *api.Client, repo ghrepo.Interface, job *shared.Job) error {
+func rerunJob(ios *iostreams.IOStreams, client *api.Client, repo ghrepo.Interface, job *shared.Job, debug bool, attempts int) error {
 	path := fmt.Sprintf("repos/%s/actions/jobs/%d/rerun", ghrepo.FullName(repo), job.ID)
 
-	err := client.REST(repo.RepoHost(), "POST", path, nil, nil)
+	body, err := rerunRequestBody(debug)
+	if err != nil {
+		return err
+	}
+
+	err = restWithRetry(ios, client, repo.RepoHost(), path, body, attempts)
 	if err != nil {
 		var httpError api.HTTPError
 		if errors.As(err, &httpError) && httpError.StatusCode == 403 {
@@ -189,3 +336,18 @@ func rerunJob(client *api.Client, repo ghrepo.Interface, job *shared.Job) error
 	}
 	return nil
 }
+
+// rerunRequestBody builds the JSON body for a rerun POST, setting
+// enable_debug_logging when the caller asked for verbose runner logs.
+func rerunRequestBody(debug bool) ([]byte, error) {
+	if !debug {
+		return nil, nil
+	}
+
+	params := map[string]interface{}{"enable_debug_logging": true}
+	b, err := json.Marshal(params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to serialize parameters: %w", err)
+	}
+	return b, nil
+}
@@ -0,0 +1,34 @@
+package rerun
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestRerunRequestBody(t *testing.T) {
+	t.Run("debug disabled", func(t *testing.T) {
+		body, err := rerunRequestBody(false)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if body != nil {
+			t.Fatalf("expected nil body, got %s", body)
+		}
+	})
+
+	t.Run("debug enabled", func(t *testing.T) {
+		body, err := rerunRequestBody(true)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		var params map[string]interface{}
+		if err := json.Unmarshal(body, &params); err != nil {
+			t.Fatalf("failed to unmarshal body: %v", err)
+		}
+
+		if enabled, _ := params["enable_debug_logging"].(bool); !enabled {
+			t.Fatalf("expected enable_debug_logging=true, got %v", params)
+		}
+	})
+}
@@ -0,0 +1,158 @@
+package rerun
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/cli/cli/v2/api"
+	"github.com/cli/cli/v2/internal/ghrepo"
+	"gopkg.in/yaml.v3"
+)
+
+// rerunAuthConfigPath is the repo-relative location of the optional rerun
+// authorization policy, following the allow-anyone / authorized-users /
+// authorized-teams / GitHub-orgs shape used by Prow's RerunAuthConfig.
+const rerunAuthConfigPath = ".github/gh-cli-rerun-auth.yml"
+
+// RerunAuthConfig restricts who may run `gh run rerun` against a repo. A nil
+// or empty config imposes no restriction.
+type RerunAuthConfig struct {
+	AllowAnyone     bool     `yaml:"allow_anyone"`
+	AuthorizedUsers []string `yaml:"authorized_users"`
+	AuthorizedTeams []string `yaml:"authorized_teams"`
+	GitHubOrgs      []string `yaml:"github_orgs"`
+}
+
+func (c *RerunAuthConfig) empty() bool {
+	return c == nil || (!c.AllowAnyone && len(c.AuthorizedUsers) == 0 && len(c.AuthorizedTeams) == 0 && len(c.GitHubOrgs) == 0)
+}
+
+// isAuthorizedUser reports whether login appears verbatim in AuthorizedUsers.
+func (c *RerunAuthConfig) isAuthorizedUser(login string) bool {
+	for _, u := range c.AuthorizedUsers {
+		if u == login {
+			return true
+		}
+	}
+	return false
+}
+
+// loadRerunAuthConfig fetches and parses the repo's rerun-auth policy, if
+// any. A missing file is not an error; it just means no restriction applies.
+func loadRerunAuthConfig(client *api.Client, repo ghrepo.Interface) (*RerunAuthConfig, error) {
+	path := fmt.Sprintf("repos/%s/contents/%s", ghrepo.FullName(repo), rerunAuthConfigPath)
+
+	var result struct {
+		Content string `json:"content"`
+	}
+	err := client.REST(repo.RepoHost(), "GET", path, nil, &result)
+	if err != nil {
+		if isHTTPNotFound(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to fetch rerun-auth config: %w", err)
+	}
+
+	raw, err := base64.StdEncoding.DecodeString(result.Content)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode rerun-auth config: %w", err)
+	}
+
+	var cfg RerunAuthConfig
+	if err := yaml.Unmarshal(raw, &cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse rerun-auth config: %w", err)
+	}
+
+	return &cfg, nil
+}
+
+func isHTTPNotFound(err error) bool {
+	var httpError api.HTTPError
+	return errors.As(err, &httpError) && httpError.StatusCode == 404
+}
+
+// checkRerunAuthorized consults the repo's rerun-auth policy and returns an
+// error if the authenticated user is not permitted to rerun workflows here.
+func checkRerunAuthorized(client *api.Client, repo ghrepo.Interface) error {
+	cfg, err := loadRerunAuthConfig(client, repo)
+	if err != nil {
+		return err
+	}
+	if cfg.empty() {
+		return nil
+	}
+	if cfg.AllowAnyone {
+		return nil
+	}
+
+	login, err := api.CurrentLoginName(client, repo.RepoHost())
+	if err != nil {
+		return fmt.Errorf("failed to determine authenticated user: %w", err)
+	}
+
+	if cfg.isAuthorizedUser(login) {
+		return nil
+	}
+
+	if len(cfg.AuthorizedTeams) > 0 {
+		authorized, err := isOnAnyTeam(client, repo, login, cfg.AuthorizedTeams)
+		if err != nil {
+			return err
+		}
+		if authorized {
+			return nil
+		}
+	}
+
+	if len(cfg.GitHubOrgs) > 0 {
+		authorized, err := isInAnyOrg(client, repo, login, cfg.GitHubOrgs)
+		if err != nil {
+			return err
+		}
+		if authorized {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("%s is not authorized to rerun workflows in %s", login, ghrepo.FullName(repo))
+}
+
+// isOnAnyTeam reports whether login is a member of any of the given
+// "org/team-slug" teams. An entry with no "/" is treated as a team-slug
+// under the repo's own owner, for convenience.
+func isOnAnyTeam(client *api.Client, repo ghrepo.Interface, login string, teams []string) (bool, error) {
+	for _, team := range teams {
+		org, slug := repo.RepoOwner(), team
+		if i := strings.Index(team, "/"); i >= 0 {
+			org, slug = team[:i], team[i+1:]
+		}
+
+		path := fmt.Sprintf("orgs/%s/teams/%s/memberships/%s", org, slug, login)
+		err := client.REST(repo.RepoHost(), "GET", path, nil, nil)
+		if err == nil {
+			return true, nil
+		}
+		if !isHTTPNotFound(err) {
+			return false, fmt.Errorf("failed to check team membership: %w", err)
+		}
+	}
+	return false, nil
+}
+
+// isInAnyOrg reports whether login is a public or private member of any of
+// the given GitHub organizations.
+func isInAnyOrg(client *api.Client, repo ghrepo.Interface, login string, orgs []string) (bool, error) {
+	for _, org := range orgs {
+		path := fmt.Sprintf("orgs/%s/members/%s", org, login)
+		err := client.REST(repo.RepoHost(), "GET", path, nil, nil)
+		if err == nil {
+			return true, nil
+		}
+		if !isHTTPNotFound(err) {
+			return false, fmt.Errorf("failed to check org membership: %w", err)
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,48 @@
+package rerun
+
+import "testing"
+
+func TestRerunAuthConfig_empty(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  *RerunAuthConfig
+		want bool
+	}{
+		{name: "nil config", cfg: nil, want: true},
+		{name: "zero value", cfg: &RerunAuthConfig{}, want: true},
+		{name: "allow anyone", cfg: &RerunAuthConfig{AllowAnyone: true}, want: false},
+		{name: "authorized users", cfg: &RerunAuthConfig{AuthorizedUsers: []string{"monalisa"}}, want: false},
+		{name: "authorized teams", cfg: &RerunAuthConfig{AuthorizedTeams: []string{"org/team"}}, want: false},
+		{name: "github orgs", cfg: &RerunAuthConfig{GitHubOrgs: []string{"org"}}, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.cfg.empty(); got != tt.want {
+				t.Errorf("empty() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRerunAuthConfig_isAuthorizedUser(t *testing.T) {
+	cfg := &RerunAuthConfig{AuthorizedUsers: []string{"monalisa", "hubot"}}
+
+	tests := []struct {
+		login string
+		want  bool
+	}{
+		{login: "monalisa", want: true},
+		{login: "hubot", want: true},
+		{login: "octocat", want: false},
+		{login: "", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.login, func(t *testing.T) {
+			if got := cfg.isAuthorizedUser(tt.login); got != tt.want {
+				t.Errorf("isAuthorizedUser(%q) = %v, want %v", tt.login, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,87 @@
+package rerun
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/cli/cli/v2/api"
+	"github.com/cli/cli/v2/pkg/iostreams"
+)
+
+// terminalStatusCodes are HTTP statuses that indicate the request itself was
+// rejected and retrying it verbatim won't help.
+var terminalStatusCodes = map[int]bool{
+	403: true,
+	404: true,
+	422: true,
+}
+
+// retryLogMu serializes retry progress lines across goroutines: batch mode
+// (batch.go) calls restWithRetry concurrently from several workers, and an
+// unsynchronized writer would interleave their output.
+var retryLogMu sync.Mutex
+
+// restWithRetry POSTs path, retrying on network errors and on any HTTP error
+// response other than those in terminalStatusCodes, up to attempts times with
+// exponential backoff and jitter. 403, 404, and 422 responses are returned
+// immediately since they are terminal.
+func restWithRetry(ios *iostreams.IOStreams, client *api.Client, host, path string, body []byte, attempts int) error {
+	if attempts < 1 {
+		attempts = 1
+	}
+
+	var err error
+	for attempt := 1; attempt <= attempts; attempt++ {
+		var reqBody io.Reader
+		if body != nil {
+			reqBody = bytes.NewReader(body)
+		}
+
+		err = client.REST(host, "POST", path, reqBody, nil)
+		if err == nil {
+			return nil
+		}
+
+		var httpError api.HTTPError
+		if errors.As(err, &httpError) && terminalStatusCodes[httpError.StatusCode] {
+			return err
+		}
+
+		if attempt == attempts {
+			break
+		}
+
+		wait := backoffWithJitter(attempt)
+		if ios.IsStderrTTY() {
+			retryLogMu.Lock()
+			fmt.Fprintf(ios.ErrOut, "request failed, retrying in %s (attempt %d/%d): %s\n", wait, attempt+1, attempts, err)
+			retryLogMu.Unlock()
+		}
+		time.Sleep(wait)
+	}
+
+	return err
+}
+
+// maxBackoff caps the exponential backoff so a large --attempts value can't
+// overflow the shift into a negative duration or sleep for absurd stretches.
+const maxBackoff = 30 * time.Second
+
+// backoffWithJitter returns an exponential backoff duration for the given
+// attempt number (1-indexed), capped at maxBackoff, with up to 50% random
+// jitter added to avoid thundering-herd retries.
+func backoffWithJitter(attempt int) time.Duration {
+	base := maxBackoff
+	if attempt < 6 { // 1<<5 seconds already exceeds maxBackoff; avoid overflowing the shift beyond that
+		if shifted := time.Duration(1<<uint(attempt-1)) * time.Second; shifted < maxBackoff {
+			base = shifted
+		}
+	}
+	jitter := time.Duration(rand.Int63n(int64(base)/2 + 1))
+	return base + jitter
+}
@@ -0,0 +1,19 @@
+package rerun
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBackoffWithJitter(t *testing.T) {
+	for attempt := 1; attempt <= 20; attempt++ {
+		wait := backoffWithJitter(attempt)
+
+		if wait <= 0 {
+			t.Fatalf("attempt %d: backoffWithJitter returned non-positive duration %s", attempt, wait)
+		}
+		if wait > maxBackoff+maxBackoff/2+time.Second {
+			t.Fatalf("attempt %d: backoffWithJitter returned %s, want at most ~%s", attempt, wait, maxBackoff+maxBackoff/2)
+		}
+	}
+}